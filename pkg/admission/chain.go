@@ -0,0 +1,50 @@
+package admission
+
+import "context"
+
+// chain runs a list of admission plug-ins in registration order, calling
+// Admit before Validate for each plug-in that implements it so mutations
+// from earlier plug-ins are visible to later ones.
+type chain []Interface
+
+// NewChain composes plug-ins into a single Interface. Plug-ins run in the
+// order given; a plug-in that returns an error from Admit or Validate stops
+// the chain.
+func NewChain(plugins ...Interface) Interface {
+	return chain(plugins)
+}
+
+func (c chain) Handles(operation Operation) bool {
+	for _, p := range c {
+		if p.Handles(operation) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c chain) Admit(ctx context.Context, a Attributes, o ObjectInterfaces) error {
+	for _, p := range c {
+		mutator, ok := p.(MutatingInterface)
+		if !ok || !mutator.Handles(a.GetOperation()) {
+			continue
+		}
+		if err := mutator.Admit(ctx, a, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c chain) Validate(ctx context.Context, a Attributes, o ObjectInterfaces) error {
+	for _, p := range c {
+		validator, ok := p.(ValidatingInterface)
+		if !ok || !validator.Handles(a.GetOperation()) {
+			continue
+		}
+		if err := validator.Validate(ctx, a, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type recordingPlugin struct {
+	name     string
+	handles  Operation
+	admitErr error
+	trace    *[]string
+}
+
+func (p *recordingPlugin) Handles(operation Operation) bool { return operation == p.handles }
+
+func (p *recordingPlugin) Admit(ctx context.Context, a Attributes, o ObjectInterfaces) error {
+	*p.trace = append(*p.trace, p.name+":admit")
+	return p.admitErr
+}
+
+func (p *recordingPlugin) Validate(ctx context.Context, a Attributes, o ObjectInterfaces) error {
+	*p.trace = append(*p.trace, p.name+":validate")
+	return nil
+}
+
+func testAttributes() Attributes {
+	return NewAttributesRecord(nil, nil, schema.GroupVersionResource{Resource: "widgets"}, "default", "widget", "", "", Update, false)
+}
+
+func TestChainRunsMutatorsInOrder(t *testing.T) {
+	var trace []string
+	chain := NewChain(
+		&recordingPlugin{name: "first", handles: Update, trace: &trace},
+		&recordingPlugin{name: "second", handles: Update, trace: &trace},
+	).(MutatingInterface)
+
+	if err := chain.Admit(context.Background(), testAttributes(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"first:admit", "second:admit"}
+	if len(trace) != len(want) {
+		t.Fatalf("got trace %v, want %v", trace, want)
+	}
+	for i := range want {
+		if trace[i] != want[i] {
+			t.Fatalf("got trace %v, want %v", trace, want)
+		}
+	}
+}
+
+func TestChainStopsOnFirstError(t *testing.T) {
+	var trace []string
+	boom := errors.New("boom")
+	chain := NewChain(
+		&recordingPlugin{name: "first", handles: Update, admitErr: boom, trace: &trace},
+		&recordingPlugin{name: "second", handles: Update, trace: &trace},
+	).(MutatingInterface)
+
+	err := chain.Admit(context.Background(), testAttributes(), nil)
+	if err != boom {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if len(trace) != 1 || trace[0] != "first:admit" {
+		t.Fatalf("expected second plug-in to be skipped once the first errors, got trace %v", trace)
+	}
+}
+
+func TestChainSkipsPluginsThatDontHandleTheOperation(t *testing.T) {
+	var trace []string
+	chain := NewChain(
+		&recordingPlugin{name: "create-only", handles: Create, trace: &trace},
+		&recordingPlugin{name: "update-only", handles: Update, trace: &trace},
+	).(MutatingInterface)
+
+	if err := chain.Admit(context.Background(), testAttributes(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(trace) != 1 || trace[0] != "update-only:admit" {
+		t.Fatalf("expected only update-only to run, got trace %v", trace)
+	}
+}
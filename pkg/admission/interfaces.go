@@ -0,0 +1,107 @@
+// Package admission exposes the plug-in surface used by Store to run
+// validating and mutating policy (in-process plugins or webhooks) inline
+// with requests. Operation covers Create, Update, Delete and Connect; Store
+// itself runs plug-ins on its Update, Apply and Delete paths.
+package admission
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Operation is the type of resource operation being checked for admission control.
+type Operation string
+
+const (
+	Create  Operation = "CREATE"
+	Update  Operation = "UPDATE"
+	Delete  Operation = "DELETE"
+	Connect Operation = "CONNECT"
+)
+
+// ObjectInterfaces provides the object typer and creater needed by plug-ins
+// that must decode or allocate objects of the admitted kind.
+type ObjectInterfaces interface {
+	GetObjectCreater() runtime.ObjectCreater
+	GetObjectTyper() runtime.ObjectTyper
+}
+
+// Attributes is the set of arguments available to admission plug-ins for a
+// single request.
+type Attributes interface {
+	GetName() string
+	GetNamespace() string
+	GetResource() schema.GroupVersionResource
+	GetSubresource() string
+	GetOperation() Operation
+	GetObject() runtime.Object
+	GetOldObject() runtime.Object
+	GetUserName() string
+	// IsDryRun reports whether the request will be validated and admitted but not persisted.
+	// Plug-ins with side effects outside the object itself (e.g. calling another service)
+	// should skip those side effects when this is true.
+	IsDryRun() bool
+}
+
+// attributesRecord is the Attributes implementation returned by NewAttributesRecord.
+type attributesRecord struct {
+	name        string
+	namespace   string
+	resource    schema.GroupVersionResource
+	subresource string
+	operation   Operation
+	object      runtime.Object
+	oldObject   runtime.Object
+	userName    string
+	dryRun      bool
+}
+
+func (r *attributesRecord) GetName() string                          { return r.name }
+func (r *attributesRecord) GetNamespace() string                     { return r.namespace }
+func (r *attributesRecord) GetResource() schema.GroupVersionResource { return r.resource }
+func (r *attributesRecord) GetSubresource() string                   { return r.subresource }
+func (r *attributesRecord) GetOperation() Operation                  { return r.operation }
+func (r *attributesRecord) GetObject() runtime.Object                { return r.object }
+func (r *attributesRecord) GetOldObject() runtime.Object             { return r.oldObject }
+func (r *attributesRecord) GetUserName() string                      { return r.userName }
+func (r *attributesRecord) IsDryRun() bool                           { return r.dryRun }
+
+// NewAttributesRecord builds an Attributes for the given operation. oldObject
+// is nil for Create and Connect.
+func NewAttributesRecord(object, oldObject runtime.Object, resource schema.GroupVersionResource, namespace, name, subresource, userName string, operation Operation, dryRun bool) Attributes {
+	return &attributesRecord{
+		name:        name,
+		namespace:   namespace,
+		resource:    resource,
+		subresource: subresource,
+		operation:   operation,
+		object:      object,
+		oldObject:   oldObject,
+		userName:    userName,
+		dryRun:      dryRun,
+	}
+}
+
+// Interface is implemented by all admission plug-ins.
+type Interface interface {
+	// Handles returns true if this plug-in cares about the given operation.
+	Handles(operation Operation) bool
+}
+
+// MutatingInterface is implemented by plug-ins that mutate the object being admitted.
+type MutatingInterface interface {
+	Interface
+
+	// Admit makes an admission decision based on the request attributes and may mutate obj.
+	Admit(ctx context.Context, a Attributes, o ObjectInterfaces) error
+}
+
+// ValidatingInterface is implemented by plug-ins that only validate and must not mutate the object.
+type ValidatingInterface interface {
+	Interface
+
+	// Validate makes an admission decision based on the request attributes and must not mutate obj.
+	Validate(ctx context.Context, a Attributes, o ObjectInterfaces) error
+}
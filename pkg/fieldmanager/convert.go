@@ -0,0 +1,59 @@
+package fieldmanager
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// managedFieldsOperation is the only operation this package records: every
+// entry ToMetaV1 writes carries "Apply", whether the ownership came from an
+// Apply or a plain Update, and FromMetaV1 ignores the operation, so the
+// stored entries do not tell applied configurations apart from updates.
+const managedFieldsOperation = metav1.ManagedFieldsOperationApply
+
+// ToMetaV1 encodes the tracker's entries as metav1.ManagedFieldsEntry so they
+// can be stored on ObjectMeta.ManagedFields. FieldsV1.Raw holds a JSON array
+// of the dotted field paths the manager owns; this is this module's own
+// compact encoding, not the upstream structured-merge-diff trie format.
+func ToMetaV1(entries []ManagedFieldsEntry) ([]metav1.ManagedFieldsEntry, error) {
+	out := make([]metav1.ManagedFieldsEntry, 0, len(entries))
+	for _, e := range entries {
+		paths := make([]string, 0, len(e.Fields))
+		for p := range e.Fields {
+			paths = append(paths, p)
+		}
+		raw, err := json.Marshal(paths)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, metav1.ManagedFieldsEntry{
+			Manager:   string(e.Manager),
+			Operation: managedFieldsOperation,
+			FieldsV1:  &metav1.FieldsV1{Raw: raw},
+		})
+	}
+	return out, nil
+}
+
+// FromMetaV1 decodes ManagedFieldsEntry values previously written by ToMetaV1.
+// Entries in a format this package didn't write (FieldsV1 nil, or not a JSON
+// array of strings) are skipped rather than treated as an error, so objects
+// that already carry upstream-style managed fields don't break Tracker setup.
+func FromMetaV1(entries []metav1.ManagedFieldsEntry) []ManagedFieldsEntry {
+	out := make([]ManagedFieldsEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.FieldsV1 == nil {
+			continue
+		}
+		var paths []string
+		if err := json.Unmarshal(e.FieldsV1.Raw, &paths); err != nil {
+			continue
+		}
+		out = append(out, ManagedFieldsEntry{
+			Manager: Manager(e.Manager),
+			Fields:  NewFieldSet(paths...),
+		})
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+// Package fieldmanager tracks, per object, which field manager last wrote
+// each field so concurrent updates from different actors (kubectl apply,
+// controllers, webhooks) can be merged instead of silently clobbering one
+// another. It is a deliberately small approximation of upstream
+// structured-merge-diff: fields are identified by dotted path rather than a
+// full typed schema, which is enough to detect and resolve ownership
+// conflicts on Store.Apply without depending on the full SMD library.
+package fieldmanager
+
+import "strconv"
+
+// Manager identifies the actor that owns a set of fields, e.g. "kubectl" or
+// a controller's FieldManager name.
+type Manager string
+
+// FieldSet is the set of field paths (e.g. "spec.replicas") a manager owns.
+type FieldSet map[string]struct{}
+
+// NewFieldSet builds a FieldSet from the given paths.
+func NewFieldSet(paths ...string) FieldSet {
+	fs := make(FieldSet, len(paths))
+	for _, p := range paths {
+		fs[p] = struct{}{}
+	}
+	return fs
+}
+
+// Union returns a new FieldSet containing the fields in either set.
+func (fs FieldSet) Union(other FieldSet) FieldSet {
+	out := make(FieldSet, len(fs)+len(other))
+	for p := range fs {
+		out[p] = struct{}{}
+	}
+	for p := range other {
+		out[p] = struct{}{}
+	}
+	return out
+}
+
+// Intersection returns the fields present in both sets.
+func (fs FieldSet) Intersection(other FieldSet) FieldSet {
+	out := FieldSet{}
+	for p := range fs {
+		if _, ok := other[p]; ok {
+			out[p] = struct{}{}
+		}
+	}
+	return out
+}
+
+// ManagedFieldsEntry records the fields a single manager owns, mirroring
+// metav1.ManagedFieldsEntry at the granularity this package supports.
+type ManagedFieldsEntry struct {
+	Manager Manager
+	Fields  FieldSet
+}
+
+// Tracker records, per manager, which fields it owns across updates to a
+// single object.
+type Tracker struct {
+	entries []ManagedFieldsEntry
+}
+
+// NewTracker builds a Tracker seeded with the object's existing managed
+// fields, e.g. as decoded from ObjectMeta.ManagedFields.
+func NewTracker(existing []ManagedFieldsEntry) *Tracker {
+	return &Tracker{entries: append([]ManagedFieldsEntry(nil), existing...)}
+}
+
+// Entries returns the tracker's current managed-fields entries, one per
+// manager that owns at least one field.
+func (t *Tracker) Entries() []ManagedFieldsEntry {
+	return append([]ManagedFieldsEntry(nil), t.entries...)
+}
+
+func (t *Tracker) ownerOf(path string) (Manager, bool) {
+	for _, e := range t.entries {
+		if _, ok := e.Fields[path]; ok {
+			return e.Manager, true
+		}
+	}
+	return "", false
+}
+
+// Update records that manager now owns fields, taking ownership away from
+// whichever manager previously held them. If force is false and any field
+// is currently owned by a different manager, Update makes no change and
+// returns the conflicting fields grouped by their current owner.
+func (t *Tracker) Update(manager Manager, fields FieldSet, force bool) (conflicts map[Manager]FieldSet, err error) {
+	conflicts = map[Manager]FieldSet{}
+	if !force {
+		for path := range fields {
+			if owner, ok := t.ownerOf(path); ok && owner != manager {
+				if conflicts[owner] == nil {
+					conflicts[owner] = FieldSet{}
+				}
+				conflicts[owner][path] = struct{}{}
+			}
+		}
+		if len(conflicts) > 0 {
+			return conflicts, &ConflictError{Conflicts: conflicts}
+		}
+	}
+
+	for i := range t.entries {
+		for path := range fields {
+			delete(t.entries[i].Fields, path)
+		}
+	}
+	t.setOwned(manager, fields)
+	return nil, nil
+}
+
+func (t *Tracker) setOwned(manager Manager, fields FieldSet) {
+	for i := range t.entries {
+		if t.entries[i].Manager == manager {
+			t.entries[i].Fields = t.entries[i].Fields.Union(fields)
+			t.pruneEmpty()
+			return
+		}
+	}
+	t.entries = append(t.entries, ManagedFieldsEntry{Manager: manager, Fields: fields})
+	t.pruneEmpty()
+}
+
+func (t *Tracker) pruneEmpty() {
+	kept := t.entries[:0]
+	for _, e := range t.entries {
+		if len(e.Fields) > 0 {
+			kept = append(kept, e)
+		}
+	}
+	t.entries = kept
+}
+
+// ConflictError is returned by Tracker.Update when fields are owned by
+// another manager and force was not set.
+type ConflictError struct {
+	Conflicts map[Manager]FieldSet
+}
+
+func (e *ConflictError) Error() string {
+	n := 0
+	for _, fs := range e.Conflicts {
+		n += len(fs)
+	}
+	return "conflict: " + pluralFields(n) + " owned by another field manager"
+}
+
+func pluralFields(n int) string {
+	if n == 1 {
+		return "1 field"
+	}
+	return strconv.Itoa(n) + " fields"
+}
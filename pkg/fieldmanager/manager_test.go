@@ -0,0 +1,84 @@
+package fieldmanager
+
+import "testing"
+
+func TestTrackerUpdateTakesOwnershipWhenUnowned(t *testing.T) {
+	tracker := NewTracker(nil)
+
+	if _, err := tracker.Update("kubectl", NewFieldSet("spec.replicas"), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	owner, ok := tracker.ownerOf("spec.replicas")
+	if !ok || owner != "kubectl" {
+		t.Fatalf("expected kubectl to own spec.replicas, got owner=%q ok=%v", owner, ok)
+	}
+}
+
+func TestTrackerUpdateConflictsWithoutForce(t *testing.T) {
+	tracker := NewTracker(nil)
+	if _, err := tracker.Update("kubectl", NewFieldSet("spec.replicas"), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conflicts, err := tracker.Update("controller", NewFieldSet("spec.replicas"), false)
+	if err == nil {
+		t.Fatalf("expected a conflict error")
+	}
+	conflictErr, ok := err.(*ConflictError)
+	if !ok {
+		t.Fatalf("expected *ConflictError, got %T", err)
+	}
+	if _, ok := conflicts["kubectl"]["spec.replicas"]; !ok {
+		t.Fatalf("expected conflicts to report kubectl as the current owner, got %v", conflicts)
+	}
+	if _, ok := conflictErr.Conflicts["kubectl"]["spec.replicas"]; !ok {
+		t.Fatalf("expected ConflictError.Conflicts to report kubectl as the current owner")
+	}
+
+	owner, _ := tracker.ownerOf("spec.replicas")
+	if owner != "kubectl" {
+		t.Fatalf("expected ownership to be unchanged after a rejected conflict, got %q", owner)
+	}
+}
+
+func TestTrackerUpdateForceTakesOwnershipDespiteConflict(t *testing.T) {
+	tracker := NewTracker(nil)
+	if _, err := tracker.Update("kubectl", NewFieldSet("spec.replicas"), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := tracker.Update("controller", NewFieldSet("spec.replicas"), true); err != nil {
+		t.Fatalf("unexpected error forcing ownership: %v", err)
+	}
+
+	owner, _ := tracker.ownerOf("spec.replicas")
+	if owner != "controller" {
+		t.Fatalf("expected controller to own spec.replicas after forcing, got %q", owner)
+	}
+
+	for _, e := range tracker.Entries() {
+		if e.Manager == "kubectl" {
+			t.Fatalf("expected kubectl's entry to be pruned once it owns no fields, got %v", e)
+		}
+	}
+}
+
+func TestToMetaV1FromMetaV1RoundTrip(t *testing.T) {
+	tracker := NewTracker(nil)
+	if _, err := tracker.Update("kubectl", NewFieldSet("spec.replicas", "spec.image"), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encoded, err := ToMetaV1(tracker.Entries())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded := FromMetaV1(encoded)
+	roundTripped := NewTracker(decoded)
+	owner, ok := roundTripped.ownerOf("spec.image")
+	if !ok || owner != "kubectl" {
+		t.Fatalf("expected kubectl to own spec.image after round-trip, got owner=%q ok=%v", owner, ok)
+	}
+}
@@ -0,0 +1,147 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// BookmarkStrategy is implemented by WatchStrategy values that can produce
+// the object carried by a periodic watch.Bookmark event. Resources that
+// don't implement it never get progress-notify, even once
+// Store.WatchBookmarkInterval is configured.
+type BookmarkStrategy interface {
+	// CurrentResourceVersion returns the resourceVersion to stamp onto the next bookmark.
+	CurrentResourceVersion(ctx context.Context) (string, error)
+	// NewBookmarkObject returns a new object of the watched kind with only
+	// ResourceVersion set, suitable for a watch.Bookmark event.
+	NewBookmarkObject(resourceVersion string) (runtime.Object, error)
+}
+
+// bookmarkHub ticks at a fixed interval and fans the tick out to every
+// watcher currently subscribed, so N concurrent watches on the same Store
+// share one ticker instead of each running their own.
+type bookmarkHub struct {
+	mu   sync.Mutex
+	subs map[int]chan struct{}
+	next int
+}
+
+func newBookmarkHub(interval time.Duration) *bookmarkHub {
+	h := &bookmarkHub{subs: map[int]chan struct{}{}}
+	go h.run(interval)
+	return h
+}
+
+func (h *bookmarkHub) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		h.mu.Lock()
+		for _, ch := range h.subs {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+		h.mu.Unlock()
+	}
+}
+
+func (h *bookmarkHub) subscribe() (id int, tick <-chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id = h.next
+	h.next++
+	ch := make(chan struct{}, 1)
+	h.subs[id] = ch
+	return id, ch
+}
+
+func (h *bookmarkHub) unsubscribe(id int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, id)
+}
+
+// bookmarkWatcher wraps a watch.Interface, injecting a watch.Bookmark event
+// every time the owning bookmarkHub ticks in addition to forwarding the
+// wrapped watch's own events.
+type bookmarkWatcher struct {
+	ctx    context.Context
+	inner  watch.Interface
+	hub    *bookmarkHub
+	subID  int
+	tick   <-chan struct{}
+	strat  BookmarkStrategy
+	out    chan watch.Event
+	stopCh chan struct{}
+	once   sync.Once
+}
+
+func newBookmarkWatcher(ctx context.Context, inner watch.Interface, hub *bookmarkHub, strat BookmarkStrategy) watch.Interface {
+	id, tick := hub.subscribe()
+	w := &bookmarkWatcher{
+		ctx:    ctx,
+		inner:  inner,
+		hub:    hub,
+		subID:  id,
+		tick:   tick,
+		strat:  strat,
+		out:    make(chan watch.Event),
+		stopCh: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *bookmarkWatcher) run() {
+	defer close(w.out)
+	defer w.hub.unsubscribe(w.subID)
+	for {
+		select {
+		case ev, ok := <-w.inner.ResultChan():
+			if !ok {
+				return
+			}
+			select {
+			case w.out <- ev:
+			case <-w.stopCh:
+				return
+			}
+		case <-w.tick:
+			rv, err := w.strat.CurrentResourceVersion(w.ctx)
+			if err != nil {
+				continue
+			}
+			obj, err := w.strat.NewBookmarkObject(rv)
+			if err != nil {
+				continue
+			}
+			select {
+			case w.out <- watch.Event{Type: watch.Bookmark, Object: obj}:
+			case <-w.stopCh:
+				return
+			}
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// Stop ends the watch. run's own exit path unsubscribes from the hub
+// regardless of why it returned, so Stop only needs to signal the goroutine
+// and release the wrapped watch.
+func (w *bookmarkWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.stopCh)
+		w.inner.Stop()
+	})
+}
+
+func (w *bookmarkWatcher) ResultChan() <-chan watch.Event {
+	return w.out
+}
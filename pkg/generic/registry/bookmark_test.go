@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+type fakeWatch struct {
+	events  chan watch.Event
+	stopped chan struct{}
+}
+
+func newFakeWatch() *fakeWatch {
+	return &fakeWatch{events: make(chan watch.Event), stopped: make(chan struct{})}
+}
+
+func (f *fakeWatch) Stop() {
+	select {
+	case <-f.stopped:
+	default:
+		close(f.stopped)
+		close(f.events)
+	}
+}
+
+func (f *fakeWatch) ResultChan() <-chan watch.Event { return f.events }
+
+type fakeBookmarkStrategy struct {
+	rv string
+}
+
+func (f *fakeBookmarkStrategy) CurrentResourceVersion(ctx context.Context) (string, error) {
+	return f.rv, nil
+}
+
+func (f *fakeBookmarkStrategy) NewBookmarkObject(resourceVersion string) (runtime.Object, error) {
+	u := &unstructured.Unstructured{}
+	u.SetResourceVersion(resourceVersion)
+	return u, nil
+}
+
+func TestBookmarkHubSubscribeUnsubscribe(t *testing.T) {
+	hub := newBookmarkHub(time.Hour)
+	id, _ := hub.subscribe()
+
+	hub.mu.Lock()
+	_, ok := hub.subs[id]
+	hub.mu.Unlock()
+	if !ok {
+		t.Fatalf("expected subscription %d to be registered", id)
+	}
+
+	hub.unsubscribe(id)
+
+	hub.mu.Lock()
+	_, ok = hub.subs[id]
+	hub.mu.Unlock()
+	if ok {
+		t.Fatalf("expected subscription %d to be removed", id)
+	}
+}
+
+func TestBookmarkWatcherEmitsBookmarkOnTick(t *testing.T) {
+	hub := newBookmarkHub(10 * time.Millisecond)
+	inner := newFakeWatch()
+	strat := &fakeBookmarkStrategy{rv: "42"}
+	w := newBookmarkWatcher(context.Background(), inner, hub, strat)
+	defer w.Stop()
+
+	select {
+	case ev := <-w.ResultChan():
+		if ev.Type != watch.Bookmark {
+			t.Fatalf("expected a Bookmark event, got %v", ev.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for a bookmark event")
+	}
+}
+
+func TestBookmarkWatcherForwardsInnerEvents(t *testing.T) {
+	hub := newBookmarkHub(time.Hour)
+	inner := newFakeWatch()
+	strat := &fakeBookmarkStrategy{rv: "1"}
+	w := newBookmarkWatcher(context.Background(), inner, hub, strat)
+	defer w.Stop()
+
+	obj := &unstructured.Unstructured{}
+	inner.events <- watch.Event{Type: watch.Added, Object: obj}
+
+	select {
+	case ev := <-w.ResultChan():
+		if ev.Type != watch.Added {
+			t.Fatalf("expected the inner Added event to be forwarded, got %v", ev.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for the forwarded event")
+	}
+}
+
+func TestBookmarkWatcherUnsubscribesWhenInnerChannelCloses(t *testing.T) {
+	hub := newBookmarkHub(time.Hour)
+	inner := newFakeWatch()
+	strat := &fakeBookmarkStrategy{rv: "1"}
+	bw := newBookmarkWatcher(context.Background(), inner, hub, strat).(*bookmarkWatcher)
+
+	// The source completes on its own, without bw.Stop() ever being called.
+	inner.Stop()
+
+	deadline := time.After(time.Second)
+	for {
+		hub.mu.Lock()
+		_, ok := hub.subs[bw.subID]
+		hub.mu.Unlock()
+		if !ok {
+			return
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("hub subscription %d was never removed after the inner watch closed", bw.subID)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/henderiw/logger/log"
+	"github.com/virtualVat/apiserver-store/pkg/admission"
+	"github.com/virtualVat/apiserver-store/pkg/rest"
+)
+
+// Delete runs the graceful-deletion pipeline for obj. If obj has finalizers,
+// or CheckGracefulDelete says the deletion should wait out a grace period,
+// Delete persists a DeletionTimestamp via UpdateStrategy instead of removing
+// the object; the actual removal happens later, the next time Store.Update
+// observes that every finalizer has cleared and DeletionGracePeriodSeconds is
+// zero or unset. deleted reports whether the object was removed from storage
+// by this call or, for a dry run, whether it would have been.
+func (r *Store) Delete(ctx context.Context, namespace, name string, obj runtime.Object, options *metav1.DeleteOptions) (out runtime.Object, deleted bool, err error) {
+	ctx, span := r.Tracer.Start(ctx, fmt.Sprintf("%s:delete", r.DefaultQualifiedResource.Resource), trace.WithAttributes())
+	defer span.End()
+
+	log := log.FromContext(ctx)
+	log.Info("delete")
+
+	if options == nil {
+		options = &metav1.DeleteOptions{}
+	}
+	if r.EnableGarbageCollection {
+		applyCascadingFinalizer(obj, options)
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	dryrun := len(options.DryRun) > 0
+
+	attr := admission.NewAttributesRecord(obj, obj, r.DefaultQualifiedResource, namespace, name, "", "", admission.Delete, dryrun)
+	if validator, ok := r.Admission.(admission.ValidatingInterface); ok && validator.Handles(admission.Delete) {
+		if err := validator.Validate(ctx, attr, deleteObjectInterfaces{typer: r.DeleteStrategy}); err != nil {
+			return nil, false, err
+		}
+	}
+
+	graceful, gracefulPending, err := rest.BeforeDelete(r.DeleteStrategy, ctx, obj, options)
+	if err != nil {
+		return nil, false, err
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if graceful || gracefulPending || len(accessor.GetFinalizers()) > 0 {
+		// finalizers alone: mark the object as terminating with no grace period
+		if !graceful && !gracefulPending {
+			now := metav1.NewTime(time.Now())
+			var zero int64
+			accessor.SetDeletionTimestamp(&now)
+			accessor.SetDeletionGracePeriodSeconds(&zero)
+		}
+		if dryrun {
+			return obj, false, nil
+		}
+		updated, err := r.UpdateStrategy.Update(ctx, key, obj, obj, false)
+		if err != nil {
+			return nil, false, err
+		}
+		return updated, false, nil
+	}
+
+	if dryrun {
+		return obj, true, nil
+	}
+	if err := r.DeleteStrategy.Delete(ctx, key, obj); err != nil {
+		return nil, false, err
+	}
+	return obj, true, nil
+}
+
+// deleteObjectInterfaces adapts a RESTDeleteStrategy's embedded ObjectTyper
+// to admission.ObjectInterfaces for plug-ins run on the delete path.
+type deleteObjectInterfaces struct {
+	typer runtime.ObjectTyper
+}
+
+func (d deleteObjectInterfaces) GetObjectCreater() runtime.ObjectCreater { return nil }
+func (d deleteObjectInterfaces) GetObjectTyper() runtime.ObjectTyper     { return d.typer }
+
+// applyCascadingFinalizer adds to obj the finalizer matching the propagation
+// policy the caller requested, unless obj already carries it: Foreground adds
+// the delete-dependents finalizer so the object survives until its dependents
+// are gone, Orphan adds the orphan-dependents finalizer, and Background or no
+// policy at all adds neither and relies on the garbage collector to delete
+// dependents independently.
+func applyCascadingFinalizer(obj runtime.Object, options *metav1.DeleteOptions) {
+	if options.PropagationPolicy == nil {
+		return
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	switch *options.PropagationPolicy {
+	case metav1.DeletePropagationForeground:
+		addFinalizer(accessor, metav1.FinalizerDeleteDependents)
+	case metav1.DeletePropagationOrphan:
+		addFinalizer(accessor, metav1.FinalizerOrphanDependents)
+	}
+}
+
+func addFinalizer(accessor metav1.Object, finalizer string) {
+	for _, f := range accessor.GetFinalizers() {
+		if f == finalizer {
+			return
+		}
+	}
+	accessor.SetFinalizers(append(accessor.GetFinalizers(), finalizer))
+}
+
+// shouldDeleteDuringUpdate reports whether a plain update should finalize
+// the delete of old instead of persisting obj: old must be terminating with
+// no grace period remaining and obj must carry no finalizers.
+func shouldDeleteDuringUpdate(obj, old metav1.Object) bool {
+	if old.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	if len(obj.GetFinalizers()) > 0 {
+		return false
+	}
+	grace := old.GetDeletionGracePeriodSeconds()
+	return grace == nil || *grace == 0
+}
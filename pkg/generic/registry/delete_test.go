@@ -0,0 +1,189 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace/noop"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeRegistryDeleteStrategy struct {
+	graceful bool
+	deleted  []types.NamespacedName
+}
+
+func (f *fakeRegistryDeleteStrategy) ObjectKinds(obj runtime.Object) ([]schema.GroupVersionKind, bool, error) {
+	return []schema.GroupVersionKind{{Group: "test", Version: "v1", Kind: "Widget"}}, false, nil
+}
+func (f *fakeRegistryDeleteStrategy) Recognizes(gvk schema.GroupVersionKind) bool { return true }
+func (f *fakeRegistryDeleteStrategy) NamespaceScoped() bool                      { return true }
+func (f *fakeRegistryDeleteStrategy) CheckGracefulDelete(ctx context.Context, obj runtime.Object, options *metav1.DeleteOptions) bool {
+	return f.graceful
+}
+func (f *fakeRegistryDeleteStrategy) Delete(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+func newTestDeleteStore(deleteStrategy *fakeRegistryDeleteStrategy, updateStrategy *fakeUpdateStrategy) *Store {
+	return &Store{
+		DefaultQualifiedResource: schema.GroupVersionResource{Resource: "widgets"},
+		UpdateStrategy:           updateStrategy,
+		DeleteStrategy:           deleteStrategy,
+		Tracer:                   noop.NewTracerProvider().Tracer("test"),
+	}
+}
+
+func TestStoreDeleteImmediate(t *testing.T) {
+	deleteStrategy := &fakeRegistryDeleteStrategy{graceful: false}
+	store := newTestDeleteStore(deleteStrategy, &fakeUpdateStrategy{})
+	obj := newTestWidget()
+
+	out, deleted, err := store.Delete(context.Background(), "default", "widget", obj, &metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Fatalf("expected deleted=true for an immediate delete")
+	}
+	if out != obj {
+		t.Fatalf("expected the object to be returned")
+	}
+	if len(deleteStrategy.deleted) != 1 {
+		t.Fatalf("expected DeleteStrategy.Delete to be called once, got %d calls", len(deleteStrategy.deleted))
+	}
+}
+
+func TestStoreDeleteDryRunImmediateDoesNotTouchStorage(t *testing.T) {
+	deleteStrategy := &fakeRegistryDeleteStrategy{graceful: false}
+	store := newTestDeleteStore(deleteStrategy, &fakeUpdateStrategy{})
+	obj := newTestWidget()
+
+	_, deleted, err := store.Delete(context.Background(), "default", "widget", obj, &metav1.DeleteOptions{DryRun: []string{"All"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Fatalf("expected a dry-run immediate delete to report deleted=true")
+	}
+	if len(deleteStrategy.deleted) != 0 {
+		t.Fatalf("expected DryRun to skip DeleteStrategy.Delete, got %d calls", len(deleteStrategy.deleted))
+	}
+}
+
+func TestStoreDeleteGracefulPersistsDeletionTimestamp(t *testing.T) {
+	deleteStrategy := &fakeRegistryDeleteStrategy{graceful: true}
+	updateStrategy := &fakeUpdateStrategy{}
+	store := newTestDeleteStore(deleteStrategy, updateStrategy)
+	obj := newTestWidget()
+
+	out, deleted, err := store.Delete(context.Background(), "default", "widget", obj, &metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted {
+		t.Fatalf("expected deleted=false for a graceful delete awaiting finalizers/grace period")
+	}
+	if len(updateStrategy.updated) != 1 {
+		t.Fatalf("expected UpdateStrategy.Update to persist the deletion timestamp, got %d calls", len(updateStrategy.updated))
+	}
+	if len(deleteStrategy.deleted) != 0 {
+		t.Fatalf("expected DeleteStrategy.Delete not to run for a graceful delete, got %d calls", len(deleteStrategy.deleted))
+	}
+	if out == nil {
+		t.Fatalf("expected a non-nil result")
+	}
+}
+
+func TestStoreDeleteDryRunGracefulDoesNotTouchStorage(t *testing.T) {
+	deleteStrategy := &fakeRegistryDeleteStrategy{graceful: true}
+	updateStrategy := &fakeUpdateStrategy{}
+	store := newTestDeleteStore(deleteStrategy, updateStrategy)
+	obj := newTestWidget()
+
+	_, deleted, err := store.Delete(context.Background(), "default", "widget", obj, &metav1.DeleteOptions{DryRun: []string{"All"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted {
+		t.Fatalf("expected deleted=false for a dry-run graceful delete")
+	}
+	if len(updateStrategy.updated) != 0 {
+		t.Fatalf("expected DryRun to skip UpdateStrategy.Update, got %d calls", len(updateStrategy.updated))
+	}
+}
+
+func TestStoreDeleteWithExistingFinalizersIsGracefulEvenIfStrategyAllowsImmediate(t *testing.T) {
+	deleteStrategy := &fakeRegistryDeleteStrategy{graceful: false}
+	updateStrategy := &fakeUpdateStrategy{}
+	store := newTestDeleteStore(deleteStrategy, updateStrategy)
+	obj := newTestWidget()
+	obj.SetFinalizers([]string{"example.com/cleanup"})
+
+	_, deleted, err := store.Delete(context.Background(), "default", "widget", obj, &metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted {
+		t.Fatalf("expected deleted=false while finalizers remain")
+	}
+	if len(updateStrategy.updated) != 1 {
+		t.Fatalf("expected the finalizer-pending object to be persisted via UpdateStrategy.Update, got %d calls", len(updateStrategy.updated))
+	}
+	if len(deleteStrategy.deleted) != 0 {
+		t.Fatalf("expected DeleteStrategy.Delete not to run while finalizers remain")
+	}
+}
+
+func TestStoreDeleteCascadingFinalizerAddedForForegroundPropagation(t *testing.T) {
+	deleteStrategy := &fakeRegistryDeleteStrategy{graceful: false}
+	updateStrategy := &fakeUpdateStrategy{}
+	store := newTestDeleteStore(deleteStrategy, updateStrategy)
+	store.EnableGarbageCollection = true
+	obj := newTestWidget()
+
+	policy := metav1.DeletePropagationForeground
+	_, deleted, err := store.Delete(context.Background(), "default", "widget", obj, &metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted {
+		t.Fatalf("expected deleted=false once a cascading finalizer is added")
+	}
+
+	found := false
+	for _, f := range obj.GetFinalizers() {
+		if f == metav1.FinalizerDeleteDependents {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected %s to be added to finalizers, got %v", metav1.FinalizerDeleteDependents, obj.GetFinalizers())
+	}
+	if len(updateStrategy.updated) != 1 {
+		t.Fatalf("expected the cascading-finalizer object to be persisted via UpdateStrategy.Update, got %d calls", len(updateStrategy.updated))
+	}
+}
+
+func TestStoreDeleteCascadingFinalizerNotAddedWithoutGarbageCollectionEnabled(t *testing.T) {
+	deleteStrategy := &fakeRegistryDeleteStrategy{graceful: false}
+	updateStrategy := &fakeUpdateStrategy{}
+	store := newTestDeleteStore(deleteStrategy, updateStrategy)
+	obj := newTestWidget()
+
+	policy := metav1.DeletePropagationForeground
+	_, deleted, err := store.Delete(context.Background(), "default", "widget", obj, &metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Fatalf("expected an immediate delete when EnableGarbageCollection is false")
+	}
+	if len(obj.GetFinalizers()) != 0 {
+		t.Fatalf("expected no finalizer to be added, got %v", obj.GetFinalizers())
+	}
+}
@@ -0,0 +1,206 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/warning"
+
+	"github.com/henderiw/logger/log"
+	"github.com/virtualVat/apiserver-store/pkg/admission"
+	"github.com/virtualVat/apiserver-store/pkg/fieldmanager"
+	"github.com/virtualVat/apiserver-store/pkg/rest"
+)
+
+// Store is the generic backing for a single resource's REST storage. It
+// delegates the behavior specific to a resource to the UpdateStrategy,
+// DeleteStrategy and WatchStrategy it is configured with, and applies the cross-cutting
+// behavior (tracing, admission) common to every resource.
+type Store struct {
+	// DefaultQualifiedResource is the resource this Store serves, used to
+	// name tracer spans and build admission attributes.
+	DefaultQualifiedResource schema.GroupVersionResource
+
+	// UpdateStrategy governs PrepareForUpdate/ValidateUpdate/Update for this resource.
+	UpdateStrategy rest.RESTUpdateStrategy
+	// DeleteStrategy governs graceful deletion for this resource.
+	DeleteStrategy rest.RESTDeleteStrategy
+	// WatchStrategy governs Watch for this resource.
+	WatchStrategy WatchStrategy
+
+	// EnableGarbageCollection makes Delete honor DeleteOptions.PropagationPolicy,
+	// adding the finalizer that corresponds to Foreground/Orphan so the
+	// garbage collector controller can delete (Foreground) or orphan (Orphan)
+	// dependents before the object itself is removed.
+	EnableGarbageCollection bool
+
+	// WatchBookmarkInterval is the interval at which Watch emits a
+	// watch.Bookmark event to watchers that set AllowWatchBookmarks, so they
+	// can advance their resourceVersion without replaying history. It is
+	// informational until WithWatchBookmarks has built the shared ticker;
+	// zero leaves bookmarks disabled.
+	WatchBookmarkInterval time.Duration
+	bookmarkHub           *bookmarkHub
+
+	// Admission is consulted, if set, for every Update, Apply and Delete
+	// request served by this Store; Delete runs only its validating plug-ins.
+	// Use WithAdmission to compose multiple plug-ins into a chain.
+	Admission admission.Interface
+
+	// Warnings, if set, receives the typed warnings reported by an UpdateStrategy that
+	// implements rest.TypedWarningsOnUpdate, applying deduplication and rate limiting before
+	// they reach the client.
+	Warnings rest.WarningRecorder
+
+	Tracer trace.Tracer
+}
+
+// WithAdmission returns a copy of r with admission plug-ins composed into a
+// chain and set as its Admission.
+func (r *Store) WithAdmission(plugins ...admission.Interface) *Store {
+	cp := *r
+	cp.Admission = admission.NewChain(plugins...)
+	return &cp
+}
+
+// WithWatchBookmarks returns a copy of r that emits a progress-notify
+// watch.Bookmark every interval to watchers that set AllowWatchBookmarks.
+// All watches served by the returned Store share a single ticker. r.WatchStrategy
+// must implement BookmarkStrategy for bookmarks to actually be emitted.
+func (r *Store) WithWatchBookmarks(interval time.Duration) *Store {
+	cp := *r
+	cp.WatchBookmarkInterval = interval
+	cp.bookmarkHub = newBookmarkHub(interval)
+	return &cp
+}
+
+// Update runs the admission-aware BeforeUpdate pipeline and persists the
+// result via the configured UpdateStrategy. options.DryRun and
+// options.FieldManager are honored as described on rest.UpdateOptions. When
+// options.FieldManager is set and fields is non-empty, Update also records
+// that manager as owning fields, unconditionally taking ownership away from
+// whichever manager held them before: unlike Apply, a plain Update never
+// rejects the request over field ownership.
+func (r *Store) Update(ctx context.Context, namespace, name string, obj, old runtime.Object, fields fieldmanager.FieldSet, options rest.UpdateOptions) (runtime.Object, error) {
+	ctx, span := r.Tracer.Start(ctx, fmt.Sprintf("%s:update", r.DefaultQualifiedResource.Resource), trace.WithAttributes())
+	defer span.End()
+
+	log := log.FromContext(ctx)
+	log.Info("update")
+
+	if options.FieldManager != "" && len(fields) > 0 {
+		manager := fieldmanager.Manager(options.FieldManager)
+		if err := r.recordFieldOwnership(obj, manager, fields, true); err != nil {
+			return nil, err
+		}
+	}
+
+	dryrun := options.IsDryRun()
+	attr := admission.NewAttributesRecord(obj, old, r.DefaultQualifiedResource, namespace, name, "", "", admission.Update, dryrun)
+	if err := rest.BeforeUpdate(r.UpdateStrategy, ctx, obj, old, r.Admission, attr, options, r.Warnings); err != nil {
+		return nil, err
+	}
+
+	updated, _, err := r.UpdateStrategy.InvokeUpdate(ctx, obj, old, false, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// An update that clears the last finalizer on a terminating object with no
+	// grace period left finalizes the earlier graceful Delete instead of
+	// persisting the update.
+	updatedAccessor, err := meta.Accessor(updated)
+	if err != nil {
+		return nil, err
+	}
+	oldAccessor, err := meta.Accessor(old)
+	if err != nil {
+		return nil, err
+	}
+	if !dryrun && r.DeleteStrategy != nil && shouldDeleteDuringUpdate(updatedAccessor, oldAccessor) {
+		key := types.NamespacedName{Namespace: namespace, Name: name}
+		if err := r.DeleteStrategy.Delete(ctx, key, updated); err != nil {
+			return nil, err
+		}
+		return updated, nil
+	}
+
+	return r.UpdateStrategy.Update(ctx, types.NamespacedName{Namespace: namespace, Name: name}, updated, old, dryrun)
+}
+
+// Apply merges appliedFields, the set of fields set in a server-side apply
+// configuration, into obj's managed fields under manager, then persists
+// obj via the UpdateStrategy's Update path. Unlike Update, Apply enforces
+// field ownership: if another manager owns one of appliedFields and
+// options.Force is false, the conflict is handled according to
+// options.FieldValidation: Strict (the default) returns a Conflict error
+// instead of persisting the object, Warn takes ownership anyway and reports
+// the conflict as a warning, and Ignore takes ownership anyway silently.
+func (r *Store) Apply(ctx context.Context, namespace, name string, obj, old runtime.Object, appliedFields fieldmanager.FieldSet, options rest.UpdateOptions) (runtime.Object, error) {
+	ctx, span := r.Tracer.Start(ctx, fmt.Sprintf("%s:apply", r.DefaultQualifiedResource.Resource), trace.WithAttributes())
+	defer span.End()
+
+	log := log.FromContext(ctx)
+	log.Info("apply")
+
+	manager := fieldmanager.Manager(options.FieldManager)
+	err := r.recordFieldOwnership(obj, manager, appliedFields, options.Force)
+	if conflict, ok := err.(*fieldmanager.ConflictError); ok && !options.Force {
+		switch options.FieldValidation {
+		case rest.FieldValidationWarn:
+			if r.Warnings != nil {
+				r.Warnings.AddWarning(ctx, r.DefaultQualifiedResource, rest.Warning{Message: conflict.Error()})
+			} else {
+				warning.AddWarning(ctx, "", conflict.Error())
+			}
+			err = r.recordFieldOwnership(obj, manager, appliedFields, true)
+		case rest.FieldValidationIgnore:
+			err = r.recordFieldOwnership(obj, manager, appliedFields, true)
+		default:
+			return nil, apierrors.NewConflict(r.DefaultQualifiedResource.GroupResource(), name, conflict)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dryrun := options.IsDryRun()
+	attr := admission.NewAttributesRecord(obj, old, r.DefaultQualifiedResource, namespace, name, "", "", admission.Update, dryrun)
+	if err := rest.BeforeUpdate(r.UpdateStrategy, ctx, obj, old, r.Admission, attr, options, r.Warnings); err != nil {
+		return nil, err
+	}
+
+	updated, _, err := r.UpdateStrategy.InvokeUpdate(ctx, obj, old, false, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.UpdateStrategy.Update(ctx, types.NamespacedName{Namespace: namespace, Name: name}, updated, old, dryrun)
+}
+
+// recordFieldOwnership assigns fields to manager in obj's managed fields. If
+// force is false and another manager already owns one of fields, obj is left
+// unmodified and the returned error is a *fieldmanager.ConflictError.
+func (r *Store) recordFieldOwnership(obj runtime.Object, manager fieldmanager.Manager, fields fieldmanager.FieldSet, force bool) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	tracker := fieldmanager.NewTracker(fieldmanager.FromMetaV1(accessor.GetManagedFields()))
+	if _, err := tracker.Update(manager, fields, force); err != nil {
+		return err
+	}
+	entries, err := fieldmanager.ToMetaV1(tracker.Entries())
+	if err != nil {
+		return err
+	}
+	accessor.SetManagedFields(entries)
+	return nil
+}
@@ -0,0 +1,195 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace/noop"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/virtualVat/apiserver-store/pkg/fieldmanager"
+	"github.com/virtualVat/apiserver-store/pkg/rest"
+)
+
+// testContext returns a context carrying the request namespace BeforeUpdate requires, the way a
+// real handler's context would.
+func testContext() context.Context {
+	return genericapirequest.WithNamespace(context.Background(), "default")
+}
+
+type fakeUpdateStrategy struct {
+	updated []runtime.Object
+}
+
+func (f *fakeUpdateStrategy) ObjectKinds(obj runtime.Object) ([]schema.GroupVersionKind, bool, error) {
+	return []schema.GroupVersionKind{{Group: "test", Version: "v1", Kind: "Widget"}}, false, nil
+}
+func (f *fakeUpdateStrategy) Recognizes(gvk schema.GroupVersionKind) bool { return true }
+func (f *fakeUpdateStrategy) NamespaceScoped() bool                      { return true }
+func (f *fakeUpdateStrategy) AllowCreateOnUpdate() bool                  { return false }
+func (f *fakeUpdateStrategy) BeginUpdate(ctx context.Context) error      { return nil }
+func (f *fakeUpdateStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {}
+func (f *fakeUpdateStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return nil
+}
+func (f *fakeUpdateStrategy) InvokeUpdate(ctx context.Context, obj, old runtime.Object, recusrion bool, options rest.UpdateOptions) (runtime.Object, runtime.Object, error) {
+	return obj, old, nil
+}
+func (f *fakeUpdateStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
+	return nil
+}
+func (f *fakeUpdateStrategy) Canonicalize(obj runtime.Object) {}
+func (f *fakeUpdateStrategy) AllowUnconditionalUpdate() bool  { return true }
+func (f *fakeUpdateStrategy) Update(ctx context.Context, key types.NamespacedName, obj, old runtime.Object, dryrun bool) (runtime.Object, error) {
+	f.updated = append(f.updated, obj)
+	return obj, nil
+}
+
+type fakeWarningRecorder struct {
+	warnings []rest.Warning
+}
+
+func (f *fakeWarningRecorder) AddWarning(ctx context.Context, resource schema.GroupVersionResource, w rest.Warning) {
+	f.warnings = append(f.warnings, w)
+}
+
+func newTestStore(strategy rest.RESTUpdateStrategy) *Store {
+	return &Store{
+		DefaultQualifiedResource: schema.GroupVersionResource{Resource: "widgets"},
+		UpdateStrategy:           strategy,
+		Tracer:                   noop.NewTracerProvider().Tracer("test"),
+	}
+}
+
+func newTestWidget() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetName("widget")
+	u.SetNamespace("default")
+	u.SetResourceVersion("1")
+	return u
+}
+
+func TestStoreUpdateRecordsFieldOwnership(t *testing.T) {
+	strategy := &fakeUpdateStrategy{}
+	store := newTestStore(strategy)
+	obj := newTestWidget()
+
+	_, err := store.Update(testContext(), "default", "widget", obj, newTestWidget(),
+		fieldmanager.NewFieldSet("spec.replicas"), rest.UpdateOptions{FieldManager: "kubectl"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := fieldmanager.FromMetaV1(obj.GetManagedFields())
+	found := false
+	for _, e := range entries {
+		if e.Manager != "kubectl" {
+			continue
+		}
+		if _, ok := e.Fields["spec.replicas"]; ok {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected kubectl to own spec.replicas after Update, got managed fields %v", entries)
+	}
+}
+
+func TestStoreUpdateOverridesExistingOwnerWithoutConflict(t *testing.T) {
+	strategy := &fakeUpdateStrategy{}
+	store := newTestStore(strategy)
+	obj := newTestWidget()
+
+	entries, err := fieldmanager.ToMetaV1([]fieldmanager.ManagedFieldsEntry{
+		{Manager: "controller", Fields: fieldmanager.NewFieldSet("spec.replicas")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj.SetManagedFields(entries)
+
+	_, err = store.Update(testContext(), "default", "widget", obj, newTestWidget(),
+		fieldmanager.NewFieldSet("spec.replicas"), rest.UpdateOptions{FieldManager: "kubectl"})
+	if err != nil {
+		t.Fatalf("expected Update to take ownership without a conflict, got error: %v", err)
+	}
+}
+
+func TestStoreApplyConflictDefaultsToStrict(t *testing.T) {
+	strategy := &fakeUpdateStrategy{}
+	store := newTestStore(strategy)
+	obj := newTestWidget()
+
+	entries, err := fieldmanager.ToMetaV1([]fieldmanager.ManagedFieldsEntry{
+		{Manager: "controller", Fields: fieldmanager.NewFieldSet("spec.replicas")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj.SetManagedFields(entries)
+
+	_, err = store.Apply(context.Background(), "default", "widget", obj, newTestWidget(),
+		fieldmanager.NewFieldSet("spec.replicas"), rest.UpdateOptions{FieldManager: "kubectl"})
+	if err == nil {
+		t.Fatalf("expected a conflict error")
+	}
+	if !errors.IsConflict(err) {
+		t.Fatalf("expected a Conflict status error, got %v", err)
+	}
+}
+
+func TestStoreApplyWarnTakesOwnershipAndReportsWarning(t *testing.T) {
+	strategy := &fakeUpdateStrategy{}
+	store := newTestStore(strategy)
+	recorder := &fakeWarningRecorder{}
+	store.Warnings = recorder
+	obj := newTestWidget()
+
+	entries, err := fieldmanager.ToMetaV1([]fieldmanager.ManagedFieldsEntry{
+		{Manager: "controller", Fields: fieldmanager.NewFieldSet("spec.replicas")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj.SetManagedFields(entries)
+
+	_, err = store.Apply(testContext(), "default", "widget", obj, newTestWidget(),
+		fieldmanager.NewFieldSet("spec.replicas"), rest.UpdateOptions{FieldManager: "kubectl", FieldValidation: rest.FieldValidationWarn})
+	if err != nil {
+		t.Fatalf("expected Warn to take ownership instead of rejecting, got error: %v", err)
+	}
+	if len(recorder.warnings) != 1 {
+		t.Fatalf("expected exactly one warning about the conflict, got %v", recorder.warnings)
+	}
+}
+
+func TestStoreApplyIgnoreTakesOwnershipSilently(t *testing.T) {
+	strategy := &fakeUpdateStrategy{}
+	store := newTestStore(strategy)
+	recorder := &fakeWarningRecorder{}
+	store.Warnings = recorder
+	obj := newTestWidget()
+
+	entries, err := fieldmanager.ToMetaV1([]fieldmanager.ManagedFieldsEntry{
+		{Manager: "controller", Fields: fieldmanager.NewFieldSet("spec.replicas")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj.SetManagedFields(entries)
+
+	_, err = store.Apply(testContext(), "default", "widget", obj, newTestWidget(),
+		fieldmanager.NewFieldSet("spec.replicas"), rest.UpdateOptions{FieldManager: "kubectl", FieldValidation: rest.FieldValidationIgnore})
+	if err != nil {
+		t.Fatalf("expected Ignore to take ownership instead of rejecting, got error: %v", err)
+	}
+	if len(recorder.warnings) != 0 {
+		t.Fatalf("expected Ignore to stay silent, got warnings %v", recorder.warnings)
+	}
+}
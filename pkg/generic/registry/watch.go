@@ -10,6 +10,19 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 )
 
+// WatchStrategy is implemented by resources that support Watch.
+type WatchStrategy interface {
+	Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error)
+}
+
+// Watch delegates to WatchStrategy for the actual event stream; honoring
+// options.ResourceVersion and options.ResourceVersionMatch (e.g. NotOlderThan)
+// is entirely up to that WatchStrategy implementation, Store does not
+// interpret either field itself. When the caller sets options.AllowWatchBookmarks,
+// r was built with WithWatchBookmarks and r.WatchStrategy implements
+// BookmarkStrategy, the returned watch.Interface also
+// emits a periodic watch.Bookmark so the client can resume later without
+// replaying history.
 func (r *Store) Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
 	ctx, span := r.Tracer.Start(ctx, fmt.Sprintf("%s:watch", r.DefaultQualifiedResource.Resource), trace.WithAttributes())
 	defer span.End()
@@ -17,5 +30,17 @@ func (r *Store) Watch(ctx context.Context, options *metainternalversion.ListOpti
 	log := log.FromContext(ctx)
 	log.Info("watch")
 
-	return r.WatchStrategy.Watch(ctx, options)
+	w, err := r.WatchStrategy.Watch(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.bookmarkHub == nil || options == nil || !options.AllowWatchBookmarks {
+		return w, nil
+	}
+	strat, ok := r.WatchStrategy.(BookmarkStrategy)
+	if !ok {
+		return w, nil
+	}
+	return newBookmarkWatcher(ctx, w, r.bookmarkHub, strat), nil
 }
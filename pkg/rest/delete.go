@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RESTDeleteStrategy defines the minimum validation and graceful-deletion
+// behavior needed to delete an object that follows Kubernetes API
+// conventions. A resource that wants unconditional, immediate deletion can
+// have CheckGracefulDelete always return false.
+type RESTDeleteStrategy interface {
+	runtime.ObjectTyper
+	// NamespaceScoped returns true if the object must be within a namespace.
+	NamespaceScoped() bool
+	// CheckGracefulDelete should return true if the object can be gracefully
+	// deleted and set any fields needed by the strategy in options.
+	CheckGracefulDelete(ctx context.Context, obj runtime.Object, options *metav1.DeleteOptions) bool
+	// Delete removes the object from storage. It is only called once all
+	// finalizers have cleared and any grace period has elapsed.
+	Delete(ctx context.Context, key types.NamespacedName, obj runtime.Object) error
+}
+
+// BeforeDelete tests whether the object can be gracefully deleted and, if
+// so, sets the deletion timestamp and grace period on obj. It returns
+// graceful=true the first time the object transitions into graceful
+// deletion, gracefulPending=true if the object is already terminating and
+// the caller should wait for finalizers to clear rather than delete it.
+func BeforeDelete(strategy RESTDeleteStrategy, ctx context.Context, obj runtime.Object, options *metav1.DeleteOptions) (graceful, gracefulPending bool, err error) {
+	if options == nil {
+		options = &metav1.DeleteOptions{}
+	}
+
+	objectMeta, _, kerr := objectMetaAndKind(strategy, obj)
+	if kerr != nil {
+		return false, false, kerr
+	}
+
+	// already terminating: the only thing a second delete can do is shorten the grace period
+	if !objectMeta.GetDeletionTimestamp().IsZero() {
+		gracePeriodSeconds := options.GracePeriodSeconds
+		if gracePeriodSeconds != nil {
+			current := objectMeta.GetDeletionGracePeriodSeconds()
+			if current == nil || *gracePeriodSeconds < *current {
+				objectMeta.SetDeletionGracePeriodSeconds(gracePeriodSeconds)
+			}
+		}
+		return false, true, nil
+	}
+
+	if !strategy.CheckGracefulDelete(ctx, obj, options) {
+		return false, false, nil
+	}
+
+	now := metav1.NewTime(time.Now())
+	gracePeriodSeconds := options.GracePeriodSeconds
+	if gracePeriodSeconds == nil {
+		var zero int64
+		gracePeriodSeconds = &zero
+	}
+	deletionTimestamp := metav1.NewTime(now.Add(time.Duration(*gracePeriodSeconds) * time.Second))
+	objectMeta.SetDeletionTimestamp(&deletionTimestamp)
+	objectMeta.SetDeletionGracePeriodSeconds(gracePeriodSeconds)
+
+	return true, false, nil
+}
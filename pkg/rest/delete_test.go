@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeDeleteStrategy struct {
+	graceful bool
+	deleted  []types.NamespacedName
+}
+
+func (f *fakeDeleteStrategy) ObjectKinds(obj runtime.Object) ([]schema.GroupVersionKind, bool, error) {
+	return []schema.GroupVersionKind{{Group: "test", Version: "v1", Kind: "Thing"}}, false, nil
+}
+func (f *fakeDeleteStrategy) Recognizes(gvk schema.GroupVersionKind) bool { return true }
+func (f *fakeDeleteStrategy) NamespaceScoped() bool                      { return true }
+func (f *fakeDeleteStrategy) CheckGracefulDelete(ctx context.Context, obj runtime.Object, options *metav1.DeleteOptions) bool {
+	return f.graceful
+}
+func (f *fakeDeleteStrategy) Delete(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+func newTestObject() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetName("widget")
+	u.SetNamespace("default")
+	return u
+}
+
+func TestBeforeDeleteImmediate(t *testing.T) {
+	strat := &fakeDeleteStrategy{graceful: false}
+	obj := newTestObject()
+
+	graceful, pending, err := BeforeDelete(strat, context.Background(), obj, &metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if graceful || pending {
+		t.Fatalf("expected immediate delete, got graceful=%v pending=%v", graceful, pending)
+	}
+}
+
+func TestBeforeDeleteGraceful(t *testing.T) {
+	strat := &fakeDeleteStrategy{graceful: true}
+	obj := newTestObject()
+
+	grace := int64(30)
+	graceful, pending, err := BeforeDelete(strat, context.Background(), obj, &metav1.DeleteOptions{GracePeriodSeconds: &grace})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !graceful || pending {
+		t.Fatalf("expected graceful=true pending=false, got graceful=%v pending=%v", graceful, pending)
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accessor.GetDeletionTimestamp().IsZero() {
+		t.Fatalf("expected deletion timestamp to be set")
+	}
+}
+
+func TestBeforeDeleteAlreadyTerminatingShortensGracePeriod(t *testing.T) {
+	strat := &fakeDeleteStrategy{graceful: true}
+	obj := newTestObject()
+	now := metav1.NewTime(time.Now())
+	obj.SetDeletionTimestamp(&now)
+	longGrace := int64(300)
+	obj.SetDeletionGracePeriodSeconds(&longGrace)
+
+	shortGrace := int64(5)
+	graceful, pending, err := BeforeDelete(strat, context.Background(), obj, &metav1.DeleteOptions{GracePeriodSeconds: &shortGrace})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if graceful || !pending {
+		t.Fatalf("expected graceful=false pending=true, got graceful=%v pending=%v", graceful, pending)
+	}
+	if got := *obj.GetDeletionGracePeriodSeconds(); got != shortGrace {
+		t.Fatalf("expected grace period shortened to %d, got %d", shortGrace, got)
+	}
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// objectMetaAndKind returns the accessor and the first recognized GroupVersionKind for obj, as
+// reported by typer.
+func objectMetaAndKind(typer runtime.ObjectTyper, obj runtime.Object) (metav1.Object, schema.GroupVersionKind, error) {
+	objectMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, schema.GroupVersionKind{}, errors.NewInternalError(err)
+	}
+	kinds, _, err := typer.ObjectKinds(obj)
+	if err != nil {
+		return nil, schema.GroupVersionKind{}, errors.NewInternalError(err)
+	}
+	return objectMeta, kinds[0], nil
+}
+
+// ExpectedNamespaceForScope returns the namespace a request is expected to operate in:
+// requestNamespace for namespace-scoped resources, empty for cluster-scoped ones.
+func ExpectedNamespaceForScope(requestNamespace string, namespaceScoped bool) string {
+	if namespaceScoped {
+		return requestNamespace
+	}
+	return metav1.NamespaceNone
+}
+
+// EnsureObjectNamespaceMatchesRequestNamespace defaults obj's namespace to expectedNamespace when
+// unset, and rejects the request if obj explicitly names a different namespace.
+func EnsureObjectNamespaceMatchesRequestNamespace(expectedNamespace string, obj metav1.Object) error {
+	objNamespace := obj.GetNamespace()
+	switch {
+	case objNamespace == expectedNamespace:
+		return nil
+	case objNamespace == metav1.NamespaceNone:
+		obj.SetNamespace(expectedNamespace)
+		return nil
+	case expectedNamespace == metav1.NamespaceNone:
+		return errors.NewBadRequest(fmt.Sprintf("the namespace of the provided object is not empty: %s", objNamespace))
+	default:
+		return errors.NewBadRequest(fmt.Sprintf("the namespace of the provided object does not match the namespace sent on the request: %q does not equal the expected namespace of %q", objNamespace, expectedNamespace))
+	}
+}
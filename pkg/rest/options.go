@@ -0,0 +1,40 @@
+package rest
+
+// FieldValidation controls how Store.Apply reacts when the fields being
+// applied are already owned by a different field manager: it does not apply
+// to ValidateUpdate or other semantic validation failures, which are always
+// rejected.
+type FieldValidation string
+
+const (
+	// FieldValidationStrict rejects the request with a Conflict error.
+	FieldValidationStrict FieldValidation = "Strict"
+	// FieldValidationWarn takes ownership of the conflicting fields anyway, reporting the
+	// conflict as a warning.
+	FieldValidationWarn FieldValidation = "Warn"
+	// FieldValidationIgnore takes ownership of the conflicting fields silently.
+	FieldValidationIgnore FieldValidation = "Ignore"
+)
+
+// UpdateOptions mirrors the update-time fields of metav1.UpdateOptions so
+// Store can thread dry-run, field-manager and field-validation behavior
+// through BeforeUpdate without depending on the meta/v1 wire types directly.
+type UpdateOptions struct {
+	// DryRun, if non-empty, means the request is validated and admitted but
+	// not persisted.
+	DryRun []string
+	// FieldManager identifies the actor performing the update for the
+	// purposes of managed-fields ownership tracking.
+	FieldManager string
+	// FieldValidation selects how Store.Apply handles a field-ownership
+	// conflict; it does not affect validation failures.
+	FieldValidation FieldValidation
+	// Force allows a field manager to take ownership of fields currently
+	// owned by another manager, overwriting any conflicts instead of
+	// rejecting the request.
+	Force bool
+}
+
+// IsDryRun reports whether the request should be validated and admitted
+// without being persisted.
+func (o UpdateOptions) IsDryRun() bool { return len(o.DryRun) > 0 }
@@ -9,10 +9,13 @@ import (
 	genericvalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/api/validation/path"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/warning"
+
+	"github.com/virtualVat/apiserver-store/pkg/admission"
 )
 
 // RESTUpdateStrategy defines the minimum validation, accepted input, and
@@ -38,7 +41,7 @@ type RESTUpdateStrategy interface {
 	// the object.
 	ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList
 	// called when async procedure is implemented by the storage layer
-	InvokeUpdate(ctx context.Context, obj, old runtime.Object, recusrion bool) (runtime.Object, runtime.Object, error)
+	InvokeUpdate(ctx context.Context, obj, old runtime.Object, recusrion bool, options UpdateOptions) (runtime.Object, runtime.Object, error)
 	// WarningsOnUpdate returns warnings to the client performing the update.
 	// WarningsOnUpdate is invoked after default fields in the object have been filled in
 	// and after ValidateUpdate has passed, before Canonicalize is called, and before the object is persisted.
@@ -73,6 +76,14 @@ type RESTUpdateStrategy interface {
 	Update(ctx context.Context, key types.NamespacedName, obj, old runtime.Object, dryrun bool) (runtime.Object, error)
 }
 
+// TypedWarningsOnUpdate is an optional extension of RESTUpdateStrategy for strategies that want
+// to report structured Warning values (field path, deprecation policy) instead of, or in addition
+// to, the plain strings returned by WarningsOnUpdate. BeforeUpdate only consults it when a
+// WarningRecorder was supplied.
+type TypedWarningsOnUpdate interface {
+	TypedWarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []Warning
+}
+
 // TODO: add other common fields that require global validation.
 func validateCommonFields(obj, old runtime.Object, strategy RESTUpdateStrategy) (field.ErrorList, error) {
 	allErrs := field.ErrorList{}
@@ -90,11 +101,33 @@ func validateCommonFields(obj, old runtime.Object, strategy RESTUpdateStrategy)
 	return allErrs, nil
 }
 
+// strategyObjectInterfaces adapts a RESTUpdateStrategy's embedded ObjectTyper
+// to admission.ObjectInterfaces. Update strategies don't carry an
+// ObjectCreater, so GetObjectCreater returns nil; plug-ins that need to
+// allocate new objects are not supported on the update path.
+type strategyObjectInterfaces struct {
+	typer runtime.ObjectTyper
+}
+
+func (s strategyObjectInterfaces) GetObjectCreater() runtime.ObjectCreater { return nil }
+func (s strategyObjectInterfaces) GetObjectTyper() runtime.ObjectTyper     { return s.typer }
+
 // BeforeUpdate ensures that common operations for all resources are performed on update. It only returns
 // errors that can be converted to api.Status. It will invoke update validation with the provided existing
 // and updated objects.
 // It sets zero values only if the object does not have a zero value for the respective field.
-func BeforeUpdate(strategy RESTUpdateStrategy, ctx context.Context, obj, old runtime.Object) error {
+//
+// If admit is non-nil and attr is non-nil, registered mutating plug-ins run after PrepareForUpdate and
+// validating plug-ins run after ValidateUpdate, matching the point at which upstream admission runs relative
+// to strategy validation.
+//
+// Common-field and strategy validation failures always reject the request; options.FieldValidation does
+// not apply here; it governs how Store.Apply reports a field-manager ownership conflict.
+//
+// If recorder is non-nil and strategy implements TypedWarningsOnUpdate, its warnings are sent
+// through recorder instead of directly through warning.AddWarning, so the caller's deduplication
+// and rate limiting applies to them.
+func BeforeUpdate(strategy RESTUpdateStrategy, ctx context.Context, obj, old runtime.Object, admit admission.Interface, attr admission.Attributes, options UpdateOptions, recorder WarningRecorder) error {
 	objectMeta, kind, kerr := objectMetaAndKind(strategy, obj)
 	if kerr != nil {
 		return kerr
@@ -118,6 +151,12 @@ func BeforeUpdate(strategy RESTUpdateStrategy, ctx context.Context, obj, old run
 
 	strategy.PrepareForUpdate(ctx, obj, old)
 
+	if mutator, ok := admit.(admission.MutatingInterface); ok && attr != nil && mutator.Handles(attr.GetOperation()) {
+		if err := mutator.Admit(ctx, attr, strategyObjectInterfaces{typer: strategy}); err != nil {
+			return err
+		}
+	}
+
 	// Use the existing UID if none is provided
 	if len(objectMeta.GetUID()) == 0 {
 		objectMeta.SetUID(oldMeta.GetUID())
@@ -146,10 +185,26 @@ func BeforeUpdate(strategy RESTUpdateStrategy, ctx context.Context, obj, old run
 		return errors.NewInvalid(kind.GroupKind(), objectMeta.GetName(), errs)
 	}
 
+	if validator, ok := admit.(admission.ValidatingInterface); ok && attr != nil && validator.Handles(attr.GetOperation()) {
+		if err := validator.Validate(ctx, attr, strategyObjectInterfaces{typer: strategy}); err != nil {
+			return err
+		}
+	}
+
 	for _, w := range strategy.WarningsOnUpdate(ctx, obj, old) {
 		warning.AddWarning(ctx, "", w)
 	}
 
+	if typed, ok := strategy.(TypedWarningsOnUpdate); ok && recorder != nil {
+		var resource schema.GroupVersionResource
+		if attr != nil {
+			resource = attr.GetResource()
+		}
+		for _, w := range typed.TypedWarningsOnUpdate(ctx, obj, old) {
+			recorder.AddWarning(ctx, resource, w)
+		}
+	}
+
 	strategy.Canonicalize(obj)
 
 	return nil
@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/warning"
+)
+
+// Severity classifies a Warning for the purposes of grouping and client display.
+type Severity string
+
+const (
+	// SeverityWarning is a general, non-deprecation warning.
+	SeverityWarning Severity = "Warning"
+	// SeverityDeprecation is a warning about a field or behavior on its way out.
+	SeverityDeprecation Severity = "Deprecation"
+)
+
+// Warning is a single structured warning about an API request, richer than
+// the plain strings RESTUpdateStrategy.WarningsOnUpdate returns: it carries
+// the field the warning applies to and, for deprecations, the versions in
+// which the field was deprecated and will be removed.
+type Warning struct {
+	// Code is the RFC 7234 warn-code to report this warning under. Zero means 299
+	// (Miscellaneous Persistent Warning), the code Kubernetes clients expect.
+	Code int
+	// FieldPath is the path to the field this warning concerns, e.g. "spec.replicas". Empty
+	// if the warning isn't about a specific field.
+	FieldPath string
+	Message   string
+	// DeprecatedInVersion, if set, marks this warning as a deprecation as of that version.
+	DeprecatedInVersion string
+	// RemovedInVersion, if set, is the version in which the field will stop working.
+	RemovedInVersion string
+}
+
+// Severity reports whether w is a plain warning or a deprecation notice.
+func (w Warning) Severity() Severity {
+	if w.DeprecatedInVersion != "" || w.RemovedInVersion != "" {
+		return SeverityDeprecation
+	}
+	return SeverityWarning
+}
+
+// Text renders w the way API clients expect warning text: "path.to.field: message", or just
+// message when FieldPath is empty.
+func (w Warning) Text() string {
+	if w.FieldPath == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.FieldPath, w.Message)
+}
+
+// HeaderValue formats w as a single RFC 7234 Warning header warning-value: `<warn-code> "-"
+// "<warn-text>"`. warn-agent is always "-" since the originating server isn't identified here.
+func (w Warning) HeaderValue() string {
+	code := w.Code
+	if code == 0 {
+		code = 299
+	}
+	return fmt.Sprintf(`%d "-" "%s"`, code, w.Text())
+}
+
+// DeprecatedFieldWarning builds a Warning reporting that the field at path has been deprecated
+// since sinceVersion and will stop working in a future release.
+func DeprecatedFieldWarning(path, sinceVersion string) Warning {
+	return Warning{
+		FieldPath:           path,
+		Message:             fmt.Sprintf("deprecated since %s; this field will stop working in a future release", sinceVersion),
+		DeprecatedInVersion: sinceVersion,
+	}
+}
+
+// GroupBySeverity groups warnings by Severity, preserving their relative order within each group.
+func GroupBySeverity(warnings []Warning) map[Severity][]Warning {
+	grouped := map[Severity][]Warning{}
+	for _, w := range warnings {
+		grouped[w.Severity()] = append(grouped[w.Severity()], w)
+	}
+	return grouped
+}
+
+// WarningRecorder receives the typed warnings generated while serving requests for potentially
+// many resources, applying deduplication and rate limiting (scoped per resource, so identical
+// warning text for one GroupVersionResource never suppresses another's) before deciding whether
+// to actually emit each one through the request's warning.AddWarning sink.
+type WarningRecorder interface {
+	AddWarning(ctx context.Context, resource schema.GroupVersionResource, w Warning)
+}
+
+// NewWarningRecorder returns a WarningRecorder that emits each distinct warning (by Text(), keyed
+// per resource) at most once per window. A resource's entries older than window are swept out the
+// next time AddWarning is called for that same resource; entries of a resource that receives no
+// further warnings are kept.
+func NewWarningRecorder(window time.Duration) WarningRecorder {
+	return &aggregatingRecorder{
+		window: window,
+		seen:   map[schema.GroupVersionResource]map[string]time.Time{},
+	}
+}
+
+type aggregatingRecorder struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[schema.GroupVersionResource]map[string]time.Time
+}
+
+func (r *aggregatingRecorder) AddWarning(ctx context.Context, resource schema.GroupVersionResource, w Warning) {
+	key := w.Text()
+	now := time.Now()
+
+	r.mu.Lock()
+	byText := r.seen[resource]
+	r.sweepLocked(byText, now)
+	if last, ok := byText[key]; ok && now.Sub(last) < r.window {
+		r.mu.Unlock()
+		return
+	}
+	if byText == nil {
+		byText = map[string]time.Time{}
+		r.seen[resource] = byText
+	}
+	byText[key] = now
+	r.mu.Unlock()
+
+	warning.AddWarning(ctx, "", w.Text())
+}
+
+// sweepLocked deletes entries older than window from byText. Callers must hold r.mu.
+func (r *aggregatingRecorder) sweepLocked(byText map[string]time.Time, now time.Time) {
+	for key, last := range byText {
+		if now.Sub(last) >= r.window {
+			delete(byText, key)
+		}
+	}
+}
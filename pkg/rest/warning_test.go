@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestWarningRecorderDedupsWithinWindow(t *testing.T) {
+	recorder := NewWarningRecorder(time.Hour).(*aggregatingRecorder)
+	widgets := schema.GroupVersionResource{Resource: "widgets"}
+
+	recorder.AddWarning(context.Background(), widgets, Warning{Message: "deprecated field"})
+	recorder.AddWarning(context.Background(), widgets, Warning{Message: "deprecated field"})
+
+	if got := len(recorder.seen[widgets]); got != 1 {
+		t.Fatalf("expected one deduped entry, got %d", got)
+	}
+}
+
+func TestWarningRecorderDoesNotCrossResourceBoundaries(t *testing.T) {
+	recorder := NewWarningRecorder(time.Hour).(*aggregatingRecorder)
+	widgets := schema.GroupVersionResource{Resource: "widgets"}
+	gadgets := schema.GroupVersionResource{Resource: "gadgets"}
+
+	recorder.AddWarning(context.Background(), widgets, Warning{Message: "deprecated field"})
+	recorder.AddWarning(context.Background(), gadgets, Warning{Message: "deprecated field"})
+
+	if _, ok := recorder.seen[widgets]["deprecated field"]; !ok {
+		t.Fatalf("expected widgets to have recorded its own warning")
+	}
+	if _, ok := recorder.seen[gadgets]["deprecated field"]; !ok {
+		t.Fatalf("expected gadgets' identical warning text to not be suppressed by widgets'")
+	}
+}
+
+func TestWarningRecorderSweepsStaleEntries(t *testing.T) {
+	recorder := NewWarningRecorder(10 * time.Millisecond).(*aggregatingRecorder)
+	widgets := schema.GroupVersionResource{Resource: "widgets"}
+
+	recorder.AddWarning(context.Background(), widgets, Warning{Message: "stale"})
+	time.Sleep(20 * time.Millisecond)
+	recorder.AddWarning(context.Background(), widgets, Warning{Message: "fresh"})
+
+	if _, ok := recorder.seen[widgets]["stale"]; ok {
+		t.Fatalf("expected the stale entry to be swept once its window elapsed")
+	}
+	if _, ok := recorder.seen[widgets]["fresh"]; !ok {
+		t.Fatalf("expected the fresh entry to remain")
+	}
+}